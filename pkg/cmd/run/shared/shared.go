@@ -0,0 +1,261 @@
+package shared
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cli/cli/api"
+	"github.com/cli/cli/internal/ghinstance"
+	"github.com/cli/cli/internal/ghrepo"
+)
+
+const (
+	// Run statuses
+	Queued     Status = "queued"
+	Completed  Status = "completed"
+	InProgress Status = "in_progress"
+	Requested  Status = "requested"
+	Waiting    Status = "waiting"
+
+	// Run conclusions
+	ActionRequired Conclusion = "action_required"
+	Cancelled      Conclusion = "cancelled"
+	Failure        Conclusion = "failure"
+	Neutral        Conclusion = "neutral"
+	Skipped        Conclusion = "skipped"
+	Stale          Conclusion = "stale"
+	StartupFailure Conclusion = "startup_failure"
+	Success        Conclusion = "success"
+	TimedOut       Conclusion = "timed_out"
+
+	AnnotationFailure Level = "failure"
+	AnnotationWarning Level = "warning"
+	AnnotationNotice  Level = "notice"
+)
+
+type Status string
+type Conclusion string
+type Level string
+
+type Run struct {
+	Name       string
+	ID         int
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Status     Status
+	Conclusion Conclusion
+	Event      string
+	HeadBranch string `json:"head_branch"`
+	JobsURL    string `json:"jobs_url"`
+	HeadSha    string `json:"head_sha"`
+	URL        string `json:"html_url"`
+	HeadCommit Commit `json:"head_commit"`
+}
+
+type Commit struct {
+	Message string
+}
+
+func (r Run) CreatedAtString() string {
+	return r.CreatedAt.String()
+}
+
+// Duration returns the run's elapsed time using now as the clock when the
+// run has not yet finished.
+func (r Run) Duration(now time.Time) time.Duration {
+	endTime := r.UpdatedAt
+	if r.Status != Completed {
+		endTime = now
+	}
+	return endTime.Sub(r.CreatedAt)
+}
+
+type Job struct {
+	ID          int
+	Status      Status
+	Conclusion  Conclusion
+	Name        string
+	Steps       Steps
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+	URL         string    `json:"html_url"`
+}
+
+type Step struct {
+	Name       string
+	Status     Status
+	Conclusion Conclusion
+	Number     int
+}
+
+type Steps []Step
+
+func (s Steps) Len() int      { return len(s) }
+func (s Steps) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s Steps) Less(i, j int) bool {
+	return s[i].Number < s[j].Number
+}
+
+type Annotation struct {
+	JobName   string
+	Message   string
+	Path      string
+	Level     Level `json:"annotation_level"`
+	StartLine int   `json:"start_line"`
+}
+
+type CheckRun struct {
+	ID int
+}
+
+type JobsPayload struct {
+	Jobs []Job
+}
+
+type RunsPayload struct {
+	WorkflowRuns []Run `json:"workflow_runs"`
+}
+
+const (
+	// Emoji used to display a conclusion in the terminal.
+	successSymbol = "✓"
+	failureSymbol = "X"
+	skippedSymbol = "-"
+)
+
+// Symbol returns the glyph and color function used to render a run or job's
+// status/conclusion.
+func Symbol(status Status, conclusion Conclusion) (string, func(string) string) {
+	if status != Completed {
+		return "*", func(s string) string { return s }
+	}
+
+	switch conclusion {
+	case Success:
+		return successSymbol, func(s string) string { return s }
+	case Skipped, Neutral:
+		return skippedSymbol, func(s string) string { return s }
+	default:
+		return failureSymbol, func(s string) string { return s }
+	}
+}
+
+func GetRun(client *http.Client, repo ghrepo.Interface, runID string) (*Run, error) {
+	var result Run
+
+	path := fmt.Sprintf("repos/%s/actions/runs/%s", ghrepo.FullName(repo), runID)
+
+	apiClient := api.NewClientFromHTTP(client)
+	err := apiClient.REST(repo.RepoHost(), "GET", path, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func GetRuns(client *http.Client, repo ghrepo.Interface, limit int) ([]Run, error) {
+	var result RunsPayload
+
+	path := fmt.Sprintf("repos/%s/actions/runs", ghrepo.FullName(repo))
+
+	apiClient := api.NewClientFromHTTP(client)
+	err := apiClient.REST(repo.RepoHost(), "GET", path, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.WorkflowRuns) > limit {
+		return result.WorkflowRuns[:limit], nil
+	}
+
+	return result.WorkflowRuns, nil
+}
+
+// GetJobs fetches the run's jobs. run.JobsURL comes straight off the API
+// response and, on a real run, is an absolute URL
+// (https://api.github.com/repos/OWNER/REPO/actions/runs/ID/jobs); strip the
+// same host prefix api.Client would otherwise prepend before handing the
+// path to apiClient.REST.
+func GetJobs(client *http.Client, repo ghrepo.Interface, run Run) ([]Job, error) {
+	var result JobsPayload
+
+	path := strings.TrimPrefix(run.JobsURL, ghinstance.RESTPrefix(repo.RepoHost()))
+	path = strings.TrimPrefix(path, "/")
+
+	apiClient := api.NewClientFromHTTP(client)
+	err := apiClient.REST(repo.RepoHost(), "GET", path, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Jobs, nil
+}
+
+func GetAnnotations(client *http.Client, repo ghrepo.Interface, job Job) ([]Annotation, error) {
+	var result []Annotation
+
+	path := fmt.Sprintf("repos/%s/check-runs/%d/annotations", ghrepo.FullName(repo), job.ID)
+
+	apiClient := api.NewClientFromHTTP(client)
+	err := apiClient.REST(repo.RepoHost(), "GET", path, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].JobName = job.Name
+	}
+
+	return result, nil
+}
+
+// GetLogArchive downloads the run's log archive (a zip of one .txt file per
+// job/step) into a temp file and opens it for reading. It resolves the
+// request URL the same way api.Client does, so it works against GHE hosts
+// (<host>/api/v3/...) as well as github.com, and reuses the same
+// authenticated client as every other request in this package.
+func GetLogArchive(client *http.Client, repo ghrepo.Interface, runID int) (*zip.ReadCloser, error) {
+	path := fmt.Sprintf("repos/%s/actions/runs/%d/logs", ghrepo.FullName(repo), runID)
+	url := ghinstance.RESTPrefix(repo.RepoHost()) + path
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode > 299 {
+		return nil, fmt.Errorf("log archive request failed (%s)", resp.Status)
+	}
+
+	tmpfile, err := ioutil.TempFile("", "gh-run-log-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	defer tmpfile.Close()
+
+	if _, err := io.Copy(tmpfile, resp.Body); err != nil {
+		os.Remove(tmpfile.Name())
+		return nil, err
+	}
+
+	zr, err := zip.OpenReader(tmpfile.Name())
+	os.Remove(tmpfile.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return zr, nil
+}
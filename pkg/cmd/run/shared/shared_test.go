@@ -0,0 +1,52 @@
+package shared
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cli/cli/internal/ghrepo"
+	"github.com/cli/cli/pkg/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetJobs(t *testing.T) {
+	tests := []struct {
+		name     string
+		jobsURL  string
+		wantPath string
+	}{
+		{
+			name:     "relative jobs_url",
+			jobsURL:  "/runs/3/jobs",
+			wantPath: "runs/3/jobs",
+		},
+		{
+			name:     "absolute jobs_url, as returned by the real API",
+			jobsURL:  "https://api.github.com/repos/OWNER/REPO/actions/runs/3/jobs",
+			wantPath: "repos/OWNER/REPO/actions/runs/3/jobs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+
+			reg.Register(
+				httpmock.REST("GET", tt.wantPath),
+				httpmock.JSONResponse(JobsPayload{
+					Jobs: []Job{{ID: 10, Name: "cool job"}},
+				}))
+
+			repo, err := ghrepo.FromFullName("OWNER/REPO")
+			assert.NoError(t, err)
+
+			run := Run{ID: 3, JobsURL: tt.jobsURL}
+			client := &http.Client{Transport: reg}
+
+			jobs, err := GetJobs(client, repo, run)
+			assert.NoError(t, err)
+			assert.Equal(t, []Job{{ID: 10, Name: "cool job"}}, jobs)
+		})
+	}
+}
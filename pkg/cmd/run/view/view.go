@@ -0,0 +1,645 @@
+package view
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/api"
+	"github.com/cli/cli/internal/ghrepo"
+	"github.com/cli/cli/pkg/cmd/run/shared"
+	"github.com/cli/cli/pkg/cmdutil"
+	"github.com/cli/cli/pkg/iostreams"
+	"github.com/cli/cli/pkg/prompt"
+	"github.com/cli/cli/utils"
+	"github.com/spf13/cobra"
+)
+
+type ViewOptions struct {
+	HttpClient func() (*http.Client, error)
+	IO         *iostreams.IOStreams
+	BaseRepo   func() (ghrepo.Interface, error)
+
+	RunID      string
+	Verbose    bool
+	ExitStatus bool
+	Prompt     bool
+
+	Log       bool
+	LogFailed bool
+
+	Exporter cmdutil.Exporter
+
+	// Watch keeps polling the run until it completes, redrawing the
+	// progress in place on a terminal.
+	Watch        bool
+	ShowProgress bool
+
+	Now func() time.Time
+
+	// Poll returns the interval to wait between watch polls. Tests override
+	// this to avoid sleeping in real time.
+	Poll func() time.Duration
+}
+
+func NewCmdView(f *cmdutil.Factory, runF func(*ViewOptions) error) *cobra.Command {
+	opts := &ViewOptions{
+		IO:         f.IOStreams,
+		HttpClient: f.HttpClient,
+		Now:        time.Now,
+		Poll:       pollInterval,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "view [<run-id>]",
+		Short: "View a summary of a workflow run",
+		Args:  cobra.MaximumNArgs(1),
+		Long: heredoc.Doc(`
+			View a summary of a workflow run.
+
+			Without an argument, the command will show a prompt to select a workflow run from
+			recent runs in the repository.
+
+			With --json, the run and its jobs are emitted as structured data instead of the
+			human-readable summary; see 'gh help formatting' for more about -q and -t.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// support `-R, --repo` override
+			opts.BaseRepo = f.BaseRepo
+
+			if len(args) == 0 && !opts.IO.CanPrompt() {
+				return &cmdutil.FlagError{Err: errors.New("run ID required when not running interactively")}
+			}
+
+			if len(args) > 0 {
+				opts.RunID = args[0]
+			} else if opts.Exporter != nil {
+				// --json is for scripting, so it requires a run ID; the
+				// "pick a run" prompt is never shown together with --json.
+				return &cmdutil.FlagError{Err: errors.New("run ID required when using --json")}
+			} else {
+				opts.Prompt = true
+			}
+
+			if opts.IO.CanPrompt() {
+				opts.ShowProgress = true
+			}
+
+			if opts.Log && opts.LogFailed {
+				return &cmdutil.FlagError{Err: errors.New("specify only one of --log or --log-failed")}
+			}
+
+			if runF != nil {
+				return runF(opts)
+			}
+			return runView(opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.ExitStatus, "exit-status", "e", false, "Exit with non-zero status if run failed")
+	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Show job steps")
+	cmd.Flags().BoolVarP(&opts.Watch, "watch", "w", false, "Show live updates until the run completes")
+	cmd.Flags().BoolVar(&opts.Log, "log", false, "View full log for a run")
+	cmd.Flags().BoolVar(&opts.LogFailed, "log-failed", false, "View the log for the failed steps of a run")
+	cmdutil.AddJSONFlags(cmd, &opts.Exporter, runFields)
+
+	return cmd
+}
+
+// runFields lists the fields available to `--json`/`-q`/`-t`.
+var runFields = []string{
+	"databaseId",
+	"name",
+	"status",
+	"conclusion",
+	"event",
+	"headBranch",
+	"headSha",
+	"url",
+	"createdAt",
+	"updatedAt",
+	"jobs",
+	"pullRequests",
+}
+
+func runView(opts *ViewOptions) error {
+	httpClient, err := opts.HttpClient()
+	if err != nil {
+		return fmt.Errorf("failed to create http client: %w", err)
+	}
+
+	repo, err := opts.BaseRepo()
+	if err != nil {
+		return fmt.Errorf("failed to determine base repo: %w", err)
+	}
+
+	runID := opts.RunID
+	if opts.Prompt {
+		runID, err = promptForRun(httpClient, repo)
+		if err != nil {
+			return err
+		}
+	}
+
+	run, err := shared.GetRun(httpClient, repo, runID)
+	if err != nil {
+		return fmt.Errorf("failed to get run: %w", err)
+	}
+
+	if opts.Watch {
+		run, err = watchRun(opts, httpClient, repo, run)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.Log || opts.LogFailed {
+		if isIncomplete(run.Status) {
+			return fmt.Errorf("run %d is still in progress; logs are not available until it completes", run.ID)
+		}
+		return displayRunLog(opts, httpClient, repo, run, opts.LogFailed)
+	}
+
+	if opts.Exporter != nil {
+		return exportRun(opts, httpClient, repo, run)
+	}
+
+	if err := renderRun(opts, httpClient, repo, run); err != nil {
+		return err
+	}
+
+	if opts.ExitStatus && isFailureState(run.Conclusion) {
+		return cmdutil.SilentError
+	}
+
+	return nil
+}
+
+func promptForRun(client *http.Client, repo ghrepo.Interface) (string, error) {
+	runs, err := shared.GetRuns(client, repo, 10)
+	if err != nil {
+		return "", fmt.Errorf("failed to get runs: %w", err)
+	}
+	if len(runs) == 0 {
+		return "", fmt.Errorf("no runs found")
+	}
+
+	candidates := []string{}
+	for _, run := range runs {
+		symbol, _ := shared.Symbol(run.Status, run.Conclusion)
+		candidates = append(candidates, fmt.Sprintf("%s %s, %s (%d)", symbol, run.HeadBranch, run.Name, run.ID))
+	}
+
+	var selected int
+	err = prompt.SurveyAskOne(&survey.Select{
+		Message: "Select a workflow run",
+		Options: candidates,
+	}, &selected)
+	if err != nil {
+		return "", fmt.Errorf("could not prompt: %w", err)
+	}
+
+	return strconv.Itoa(runs[selected].ID), nil
+}
+
+// watchRun polls the run and its jobs on a jittered 2-5s interval, redrawing
+// the in-progress state in place on a terminal (and appending each poll's
+// output otherwise), until the run leaves the in_progress,
+// queued or requested states. It returns the final run without rendering it;
+// the caller renders the completed run through the normal render path so
+// the run is only ever fully rendered once.
+func watchRun(opts *ViewOptions, client *http.Client, repo ghrepo.Interface, run *shared.Run) (*shared.Run, error) {
+	out := opts.IO.Out
+	cs := opts.IO.ColorScheme()
+
+	for isIncomplete(run.Status) {
+		jobs, err := shared.GetJobs(client, repo, *run)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get jobs: %w", err)
+		}
+
+		if opts.IO.IsStdoutTTY() {
+			opts.IO.RefreshScreen()
+		}
+
+		now := opts.Now()
+		fmt.Fprintln(out, runSummary(cs, run, now))
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, cs.Bold("JOBS"))
+		for _, job := range jobs {
+			fmt.Fprintln(out, watchJobLine(cs, job, now))
+		}
+
+		time.Sleep(opts.Poll())
+
+		run, err = shared.GetRun(client, repo, strconv.Itoa(run.ID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get run: %w", err)
+		}
+	}
+
+	return run, nil
+}
+
+// pollInterval returns a base interval of 2s plus up to 3s of jitter, so that
+// concurrent `gh run view --watch` invocations don't all hit the API at once.
+func pollInterval() time.Duration {
+	return 2*time.Second + time.Duration(rand.Int63n(int64(3*time.Second)))
+}
+
+func isIncomplete(status shared.Status) bool {
+	return status == shared.InProgress || status == shared.Queued || status == shared.Requested
+}
+
+func isFailureState(c shared.Conclusion) bool {
+	switch c {
+	case shared.Failure, shared.Cancelled, shared.TimedOut, shared.ActionRequired, shared.StartupFailure:
+		return true
+	default:
+		return false
+	}
+}
+
+// jobLine renders a job's row for the static (post-completion) JOBS listing.
+func jobLine(cs *iostreams.ColorScheme, job shared.Job) string {
+	symbol, symbolColor := shared.Symbol(job.Status, job.Conclusion)
+	return fmt.Sprintf("%s %s (ID %d)", symbolColor(symbol), job.Name, job.ID)
+}
+
+// watchJobLine renders a job's row while a run is being watched: its elapsed
+// time and, for each of its steps, a spinner while the step is in progress or
+// its final conclusion glyph once it completes.
+func watchJobLine(cs *iostreams.ColorScheme, job shared.Job, now time.Time) string {
+	symbol, symbolColor := shared.Symbol(job.Status, job.Conclusion)
+	if job.Status != shared.Completed {
+		symbol = spinner()
+	}
+
+	elapsed := job.CompletedAt.Sub(job.StartedAt)
+	if job.Status != shared.Completed {
+		elapsed = now.Sub(job.StartedAt)
+	}
+
+	line := fmt.Sprintf("%s %s (ID %d) %s", symbolColor(symbol), job.Name, job.ID, elapsed.Truncate(time.Second))
+
+	for _, step := range job.Steps {
+		stepSymbol, stepColor := shared.Symbol(step.Status, step.Conclusion)
+		if step.Status != shared.Completed {
+			stepSymbol = spinner()
+		}
+		line += fmt.Sprintf("\n  %s %s", stepColor(stepSymbol), step.Name)
+	}
+
+	return line
+}
+
+var spinnerFrames = []string{"-", "\\", "|", "/"}
+var spinnerIdx int
+
+// spinner returns the next frame of a simple rotating spinner used for
+// in_progress jobs and steps while watching a run.
+func spinner() string {
+	frame := spinnerFrames[spinnerIdx%len(spinnerFrames)]
+	spinnerIdx++
+	return frame
+}
+
+func runSummary(cs *iostreams.ColorScheme, run *shared.Run, now time.Time) string {
+	symbol, symbolColor := shared.Symbol(run.Status, run.Conclusion)
+	return fmt.Sprintf("%s %s %s · %d\nTriggered via %s %s",
+		symbolColor(symbol), run.HeadBranch, run.Name, run.ID, run.Event, utils.FuzzyAgo(now.Sub(run.CreatedAt)))
+}
+
+// pullRequestForRun looks up the pull request, if any, associated with the
+// run's head branch and commit. It is best-effort: a lookup failure should
+// not prevent the rest of the run from rendering.
+func pullRequestForRun(client *http.Client, repo ghrepo.Interface, run shared.Run) (*api.PullRequest, error) {
+	type response struct {
+		Repository struct {
+			PullRequests struct {
+				Nodes []api.PullRequest
+			}
+		}
+	}
+
+	query := `
+	query PullRequestForRun($owner: String!, $repo: String!, $headRefName: String!) {
+		repository(owner: $owner, name: $repo) {
+			pullRequests(headRefName: $headRefName, first: 1, orderBy: { field: CREATED_AT, direction: DESC }) {
+				nodes {
+					number
+					title
+					headRepositoryOwner {
+						login
+					}
+				}
+			}
+		}
+	}`
+
+	variables := map[string]interface{}{
+		"owner":       repo.RepoOwner(),
+		"repo":        repo.RepoName(),
+		"headRefName": run.HeadBranch,
+	}
+
+	var resp response
+	apiClient := api.NewClientFromHTTP(client)
+	if err := apiClient.GraphQL(repo.RepoHost(), query, variables, &resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Repository.PullRequests.Nodes) == 0 {
+		return nil, nil
+	}
+
+	pr := resp.Repository.PullRequests.Nodes[0]
+	return &pr, nil
+}
+
+// displayRunLog downloads the run's log archive, extracts it to a temp file,
+// and writes the log for each job/step to opts.IO.Out. When failedOnly is
+// true, only jobs that concluded in failure and their non-successful steps
+// are printed.
+func displayRunLog(opts *ViewOptions, client *http.Client, repo ghrepo.Interface, run *shared.Run, failedOnly bool) error {
+	jobs, err := shared.GetJobs(client, repo, *run)
+	if err != nil {
+		return fmt.Errorf("failed to get jobs: %w", err)
+	}
+
+	zr, err := shared.GetLogArchive(client, repo, run.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get run log: %w", err)
+	}
+	defer zr.Close()
+
+	out := opts.IO.ColorableOut()
+	cs := opts.IO.ColorScheme()
+
+	for _, job := range jobs {
+		if failedOnly && job.Conclusion != shared.Failure {
+			continue
+		}
+
+		for _, step := range job.Steps {
+			if failedOnly && step.Conclusion == shared.Success {
+				continue
+			}
+
+			entry := findLogEntry(zr, job.Name, step)
+			if entry == nil {
+				continue
+			}
+
+			fmt.Fprintf(out, "==> %s / %s\n", job.Name, step.Name)
+			if err := copyLogEntry(out, cs, entry); err != nil {
+				return fmt.Errorf("failed to read log for %s/%s: %w", job.Name, step.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// findLogEntry locates the zip entry for a job's step. The archive lays out
+// entries as "<job name>/<step number>_<step name>.txt".
+func findLogEntry(zr *zip.ReadCloser, jobName string, step shared.Step) *zip.File {
+	name := fmt.Sprintf("%s/%d_%s.txt", jobName, step.Number, step.Name)
+	for _, f := range zr.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// copyLogEntry streams a log entry to out, colorizing GitHub Actions
+// ##[error]/##[warning] prefixed lines when the terminal supports color.
+func copyLogEntry(out io.Writer, cs *iostreams.ColorScheme, f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	b, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(string(b), "\n"), "\n") {
+		switch {
+		case strings.Contains(line, "##[error]"):
+			fmt.Fprintln(out, cs.Red(line))
+		case strings.Contains(line, "##[warning]"):
+			fmt.Fprintln(out, cs.Yellow(line))
+		default:
+			fmt.Fprintln(out, line)
+		}
+	}
+
+	return nil
+}
+
+func renderRun(opts *ViewOptions, client *http.Client, repo ghrepo.Interface, run *shared.Run) error {
+	out := opts.IO.Out
+	cs := opts.IO.ColorScheme()
+
+	jobs, err := shared.GetJobs(client, repo, *run)
+	if err != nil {
+		return fmt.Errorf("failed to get jobs: %w", err)
+	}
+
+	// best-effort; a run may not have an associated pull request, and a
+	// lookup failure shouldn't prevent the rest of the run from rendering
+	pr, err := pullRequestForRun(client, repo, *run)
+	if err != nil {
+		pr = nil
+	}
+
+	var annotations []shared.Annotation
+	for _, job := range jobs {
+		as, err := shared.GetAnnotations(client, repo, job)
+		if err != nil {
+			return fmt.Errorf("failed to get annotations: %w", err)
+		}
+		annotations = append(annotations, as...)
+	}
+
+	if !opts.Verbose {
+		annotations = withoutNoticeLevel(annotations)
+	}
+	sortAnnotationsBySeverity(annotations)
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, runSummary(cs, run, opts.Now()))
+	if pr != nil {
+		fmt.Fprintf(out, "Related pull request: #%d %s\n", pr.Number, pr.Title)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, cs.Bold("JOBS"))
+	for _, job := range jobs {
+		fmt.Fprintln(out, jobLine(cs, job))
+	}
+
+	if len(annotations) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, cs.Bold("ANNOTATIONS"))
+		for _, a := range annotations {
+			fmt.Fprintln(out, annotationLine(cs, a))
+		}
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "For more information about a job, try: gh job view <job-id>\n")
+	fmt.Fprintf(out, "view this run on GitHub: %s\n", run.URL)
+
+	return nil
+}
+
+// annotationSeverity ranks annotation levels from most to least severe so
+// that failures surface before warnings and notices.
+func annotationSeverity(level shared.Level) int {
+	switch level {
+	case shared.AnnotationFailure:
+		return 0
+	case shared.AnnotationWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// sortAnnotationsBySeverity orders annotations failure, then warning, then
+// notice, preserving relative order within the same level.
+func sortAnnotationsBySeverity(annotations []shared.Annotation) {
+	sort.SliceStable(annotations, func(i, j int) bool {
+		return annotationSeverity(annotations[i].Level) < annotationSeverity(annotations[j].Level)
+	})
+}
+
+// withoutNoticeLevel filters out notice-level annotations, which are hidden
+// unless --verbose is passed.
+func withoutNoticeLevel(annotations []shared.Annotation) []shared.Annotation {
+	filtered := make([]shared.Annotation, 0, len(annotations))
+	for _, a := range annotations {
+		if a.Level != shared.AnnotationNotice {
+			filtered = append(filtered, a)
+		}
+	}
+	return filtered
+}
+
+// annotationSymbol returns the glyph and color function used to render an
+// annotation's level, mirroring shared.Symbol's run/job styling.
+func annotationSymbol(cs *iostreams.ColorScheme, level shared.Level) (string, func(string) string) {
+	switch level {
+	case shared.AnnotationFailure:
+		return "X", cs.Red
+	case shared.AnnotationWarning:
+		return "!", cs.Yellow
+	default:
+		return "-", cs.Blue
+	}
+}
+
+func annotationLine(cs *iostreams.ColorScheme, a shared.Annotation) string {
+	symbol, symbolColor := annotationSymbol(cs, a.Level)
+	return fmt.Sprintf("%s %s  %s\n   %s:%d", symbolColor(symbol), a.Level, a.Message, a.Path, a.StartLine)
+}
+
+type jobExport struct {
+	Name        string       `json:"name"`
+	Status      string       `json:"status"`
+	Conclusion  string       `json:"conclusion"`
+	StartedAt   time.Time    `json:"startedAt"`
+	CompletedAt time.Time    `json:"completedAt"`
+	Steps       []stepExport `json:"steps"`
+}
+
+type stepExport struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	Number     int    `json:"number"`
+}
+
+type pullRequestExport struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+type runExport struct {
+	DatabaseID   int                 `json:"databaseId"`
+	Name         string              `json:"name"`
+	Status       string              `json:"status"`
+	Conclusion   string              `json:"conclusion"`
+	Event        string              `json:"event"`
+	HeadBranch   string              `json:"headBranch"`
+	HeadSha      string              `json:"headSha"`
+	URL          string              `json:"url"`
+	CreatedAt    time.Time           `json:"createdAt"`
+	UpdatedAt    time.Time           `json:"updatedAt"`
+	Jobs         []jobExport         `json:"jobs"`
+	PullRequests []pullRequestExport `json:"pullRequests"`
+}
+
+// exportRun fetches the data needed to satisfy opts.Exporter's fields and
+// writes the projected JSON (or the result of a -q/-t transform) to opts.IO.Out.
+func exportRun(opts *ViewOptions, client *http.Client, repo ghrepo.Interface, run *shared.Run) error {
+	jobs, err := shared.GetJobs(client, repo, *run)
+	if err != nil {
+		return fmt.Errorf("failed to get jobs: %w", err)
+	}
+
+	export := runExport{
+		DatabaseID: run.ID,
+		Name:       run.Name,
+		Status:     string(run.Status),
+		Conclusion: string(run.Conclusion),
+		Event:      run.Event,
+		HeadBranch: run.HeadBranch,
+		HeadSha:    run.HeadSha,
+		URL:        run.URL,
+		CreatedAt:  run.CreatedAt,
+		UpdatedAt:  run.UpdatedAt,
+	}
+
+	for _, job := range jobs {
+		je := jobExport{
+			Name:        job.Name,
+			Status:      string(job.Status),
+			Conclusion:  string(job.Conclusion),
+			StartedAt:   job.StartedAt,
+			CompletedAt: job.CompletedAt,
+		}
+		for _, step := range job.Steps {
+			je.Steps = append(je.Steps, stepExport{
+				Name:       step.Name,
+				Status:     string(step.Status),
+				Conclusion: string(step.Conclusion),
+				Number:     step.Number,
+			})
+		}
+		export.Jobs = append(export.Jobs, je)
+	}
+
+	if pr, err := pullRequestForRun(client, repo, *run); err == nil && pr != nil {
+		export.PullRequests = append(export.PullRequests, pullRequestExport{
+			Number: pr.Number,
+			Title:  pr.Title,
+		})
+	}
+
+	return opts.Exporter.Write(opts.IO, export)
+}
@@ -0,0 +1,97 @@
+package view
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cli/cli/internal/ghrepo"
+	"github.com/cli/cli/pkg/cmd/run/shared"
+	"github.com/cli/cli/pkg/httpmock"
+	"github.com/cli/cli/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunView_annotations(t *testing.T) {
+	created, _ := time.Parse("2006-01-02 15:04:05", "2021-02-23 04:51:00")
+	updated, _ := time.Parse("2006-01-02 15:04:05", "2021-02-23 04:55:34")
+
+	run := shared.Run{
+		ID:         11,
+		Name:       "annotated",
+		Status:     shared.Completed,
+		Conclusion: shared.Failure,
+		CreatedAt:  created,
+		UpdatedAt:  updated,
+		HeadBranch: "trunk",
+		Event:      "push",
+		JobsURL:    "/runs/11/jobs",
+		URL:        "runs/11",
+	}
+
+	tests := []struct {
+		name    string
+		verbose bool
+		wantOut string
+	}{
+		{
+			name:    "hides notices by default",
+			verbose: false,
+			wantOut: "\nX trunk annotated · 11\nTriggered via push about 59 minutes ago\n\nJOBS\nX job one (ID 20)\nX job two (ID 21)\n\nANNOTATIONS\nX failure  build failed\n   main.go:10\n! warning  deprecated call\n   main.go:20\n\nFor more information about a job, try: gh job view <job-id>\nview this run on GitHub: runs/11\n",
+		},
+		{
+			name:    "includes notices when verbose",
+			verbose: true,
+			wantOut: "\nX trunk annotated · 11\nTriggered via push about 59 minutes ago\n\nJOBS\nX job one (ID 20)\nX job two (ID 21)\n\nANNOTATIONS\nX failure  build failed\n   main.go:10\n! warning  deprecated call\n   main.go:20\n- notice  fyi\n   main.go:30\n\nFor more information about a job, try: gh job view <job-id>\nview this run on GitHub: runs/11\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+
+			reg.Register(
+				httpmock.REST("GET", "repos/OWNER/REPO/actions/runs/11"),
+				httpmock.JSONResponse(run))
+			reg.Register(
+				httpmock.GraphQL(`query PullRequestForRun`),
+				httpmock.StringResponse(``))
+			reg.Register(
+				httpmock.REST("GET", "runs/11/jobs"),
+				httpmock.JSONResponse(shared.JobsPayload{
+					Jobs: []shared.Job{
+						{ID: 20, Name: "job one", Status: shared.Completed, Conclusion: shared.Failure},
+						{ID: 21, Name: "job two", Status: shared.Completed, Conclusion: shared.Failure},
+					},
+				}))
+			reg.Register(
+				httpmock.REST("GET", "repos/OWNER/REPO/check-runs/20/annotations"),
+				httpmock.JSONResponse([]shared.Annotation{
+					{Level: shared.AnnotationFailure, Message: "build failed", Path: "main.go", StartLine: 10},
+					{Level: shared.AnnotationNotice, Message: "fyi", Path: "main.go", StartLine: 30},
+				}))
+			reg.Register(
+				httpmock.REST("GET", "repos/OWNER/REPO/check-runs/21/annotations"),
+				httpmock.JSONResponse([]shared.Annotation{
+					{Level: shared.AnnotationWarning, Message: "deprecated call", Path: "main.go", StartLine: 20},
+				}))
+
+			io, _, stdout, _ := iostreams.Test()
+			io.SetStdoutTTY(true)
+
+			opts := &ViewOptions{
+				RunID:      "11",
+				Verbose:    tt.verbose,
+				IO:         io,
+				Now:        func() time.Time { n, _ := time.Parse("2006-01-02 15:04:05", "2021-02-23 05:50:00"); return n },
+				HttpClient: func() (*http.Client, error) { return &http.Client{Transport: reg}, nil },
+				BaseRepo:   func() (ghrepo.Interface, error) { return ghrepo.FromFullName("OWNER/REPO") },
+			}
+
+			err := runView(opts)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantOut, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package view
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cli/cli/internal/ghrepo"
+	"github.com/cli/cli/pkg/cmd/run/shared"
+	"github.com/cli/cli/pkg/cmdutil"
+	"github.com/cli/cli/pkg/httpmock"
+	"github.com/cli/cli/pkg/iostreams"
+	"github.com/google/shlex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCmdView_jsonSkipsPromptWithoutRunID(t *testing.T) {
+	io, _, _, _ := iostreams.Test()
+	io.SetStdinTTY(true)
+	io.SetStdoutTTY(true)
+
+	f := &cmdutil.Factory{IOStreams: io}
+
+	argv, err := shlex.Split("--json jobs")
+	assert.NoError(t, err)
+
+	cmd := NewCmdView(f, func(opts *ViewOptions) error { return nil })
+	cmd.SetArgs(argv)
+	cmd.SetIn(&bytes.Buffer{})
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+
+	_, err = cmd.ExecuteC()
+	assert.Error(t, err)
+}
+
+func TestRunView_json(t *testing.T) {
+	created, _ := time.Parse("2006-01-02 15:04:05", "2021-02-23 04:51:00")
+	updated, _ := time.Parse("2006-01-02 15:04:05", "2021-02-23 04:55:34")
+
+	run := shared.Run{
+		ID:         12,
+		Name:       "json run",
+		Status:     shared.Completed,
+		Conclusion: shared.Success,
+		CreatedAt:  created,
+		UpdatedAt:  updated,
+		HeadBranch: "trunk",
+		HeadSha:    "abc123",
+		Event:      "push",
+		JobsURL:    "/runs/12/jobs",
+		URL:        "runs/12",
+	}
+
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	reg.Register(
+		httpmock.REST("GET", "repos/OWNER/REPO/actions/runs/12"),
+		httpmock.JSONResponse(run))
+	reg.Register(
+		httpmock.REST("GET", "runs/12/jobs"),
+		httpmock.JSONResponse(shared.JobsPayload{
+			Jobs: []shared.Job{
+				{Name: "build", Status: shared.Completed, Conclusion: shared.Success},
+				{Name: "test", Status: shared.Completed, Conclusion: shared.Success},
+			},
+		}))
+	reg.Register(
+		httpmock.GraphQL(`query PullRequestForRun`),
+		httpmock.StringResponse(``))
+
+	io, _, stdout, _ := iostreams.Test()
+
+	exporter := cmdutil.NewJSONExporter()
+	exporter.SetFields([]string{"jobs"})
+
+	opts := &ViewOptions{
+		RunID:      "12",
+		IO:         io,
+		Now:        time.Now,
+		Exporter:   exporter,
+		HttpClient: func() (*http.Client, error) { return &http.Client{Transport: reg}, nil },
+		BaseRepo:   func() (ghrepo.Interface, error) { return ghrepo.FromFullName("OWNER/REPO") },
+	}
+
+	err := runView(opts)
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), `"name":"build"`)
+	assert.Contains(t, stdout.String(), `"name":"test"`)
+}
+
+func TestRunView_json_jqFilter(t *testing.T) {
+	created, _ := time.Parse("2006-01-02 15:04:05", "2021-02-23 04:51:00")
+	updated, _ := time.Parse("2006-01-02 15:04:05", "2021-02-23 04:55:34")
+
+	run := shared.Run{
+		ID:         12,
+		Name:       "json run",
+		Status:     shared.Completed,
+		Conclusion: shared.Success,
+		CreatedAt:  created,
+		UpdatedAt:  updated,
+		HeadBranch: "trunk",
+		HeadSha:    "abc123",
+		Event:      "push",
+		JobsURL:    "/runs/12/jobs",
+		URL:        "runs/12",
+	}
+
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	reg.Register(
+		httpmock.REST("GET", "repos/OWNER/REPO/actions/runs/12"),
+		httpmock.JSONResponse(run))
+	reg.Register(
+		httpmock.REST("GET", "runs/12/jobs"),
+		httpmock.JSONResponse(shared.JobsPayload{
+			Jobs: []shared.Job{
+				{Name: "build", Status: shared.Completed, Conclusion: shared.Success},
+				{Name: "test", Status: shared.Completed, Conclusion: shared.Success},
+			},
+		}))
+	reg.Register(
+		httpmock.GraphQL(`query PullRequestForRun`),
+		httpmock.StringResponse(``))
+
+	io, _, stdout, _ := iostreams.Test()
+
+	f := &cmdutil.Factory{
+		IOStreams:  io,
+		HttpClient: func() (*http.Client, error) { return &http.Client{Transport: reg}, nil },
+		BaseRepo:   func() (ghrepo.Interface, error) { return ghrepo.FromFullName("OWNER/REPO") },
+	}
+
+	cmd := NewCmdView(f, nil)
+	argv, err := shlex.Split("12 --json jobs -q .jobs[].name")
+	assert.NoError(t, err)
+	cmd.SetArgs(argv)
+	cmd.SetIn(&bytes.Buffer{})
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+
+	_, err = cmd.ExecuteC()
+	assert.NoError(t, err)
+	assert.Equal(t, "build\ntest\n", stdout.String())
+}
@@ -0,0 +1,132 @@
+package view
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cli/cli/internal/ghrepo"
+	"github.com/cli/cli/pkg/cmd/run/shared"
+	"github.com/cli/cli/pkg/httpmock"
+	"github.com/cli/cli/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func zipResponder(body []byte) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Request:    req,
+			Body:       ioutil.NopCloser(bytes.NewReader(body)),
+			Header:     make(http.Header),
+		}, nil
+	}
+}
+
+func buildLogZip(t *testing.T, entries map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range entries {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestRunView_log(t *testing.T) {
+	created, _ := time.Parse("2006-01-02 15:04:05", "2021-02-23 04:51:00")
+	updated, _ := time.Parse("2006-01-02 15:04:05", "2021-02-23 04:55:34")
+
+	run := shared.Run{
+		ID:         10,
+		Name:       "logs",
+		Status:     shared.Completed,
+		Conclusion: shared.Failure,
+		CreatedAt:  created,
+		UpdatedAt:  updated,
+		HeadBranch: "trunk",
+		Event:      "push",
+		JobsURL:    "/runs/10/jobs",
+		URL:        "runs/10",
+	}
+
+	tests := []struct {
+		name       string
+		failedOnly bool
+		wantOut    string
+	}{
+		{
+			name:       "failed job",
+			failedOnly: true,
+			wantOut:    "==> sad job / build\nbuild blew up\n##[error]boom\n",
+		},
+		{
+			name:       "full log",
+			failedOnly: false,
+			wantOut:    "==> happy job / build\nall good\n==> sad job / build\nbuild blew up\n##[error]boom\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+
+			reg.Register(
+				httpmock.REST("GET", "repos/OWNER/REPO/actions/runs/10"),
+				httpmock.JSONResponse(run))
+			reg.Register(
+				httpmock.REST("GET", "runs/10/jobs"),
+				httpmock.JSONResponse(shared.JobsPayload{
+					Jobs: []shared.Job{
+						{
+							Name:       "happy job",
+							Conclusion: shared.Success,
+							Status:     shared.Completed,
+							Steps: shared.Steps{
+								{Name: "build", Number: 1, Conclusion: shared.Success, Status: shared.Completed},
+							},
+						},
+						{
+							Name:       "sad job",
+							Conclusion: shared.Failure,
+							Status:     shared.Completed,
+							Steps: shared.Steps{
+								{Name: "build", Number: 1, Conclusion: shared.Failure, Status: shared.Completed},
+							},
+						},
+					},
+				}))
+			reg.Register(
+				httpmock.REST("GET", "repos/OWNER/REPO/actions/runs/10/logs"),
+				zipResponder(buildLogZip(t, map[string]string{
+					"happy job/1_build.txt": "all good\n",
+					"sad job/1_build.txt":   "build blew up\n##[error]boom\n",
+				})))
+
+			io, _, stdout, _ := iostreams.Test()
+
+			opts := &ViewOptions{
+				RunID:      "10",
+				Log:        !tt.failedOnly,
+				LogFailed:  tt.failedOnly,
+				IO:         io,
+				Now:        time.Now,
+				HttpClient: func() (*http.Client, error) { return &http.Client{Transport: reg}, nil },
+				BaseRepo:   func() (ghrepo.Interface, error) { return ghrepo.FromFullName("OWNER/REPO") },
+			}
+
+			err := runView(opts)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantOut, stdout.String())
+		})
+	}
+}
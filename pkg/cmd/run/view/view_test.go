@@ -151,15 +151,55 @@ func TestViewRun(t *testing.T) {
 		wantErr  bool
 		wantOut  string
 	}{
-		// TODO found matching PR
-		// TODO did not find matching PR
-		// TODO failed job
-		// TODO no failed job
 		// TODO verbose
 		// TODO exit status
 		// TODO pass in a run id
 		{
-			name: "prompts for choice",
+			name: "found matching PR",
+			tty:  true,
+			stubs: func(reg *httpmock.Registry) {
+				reg.Register(
+					httpmock.REST("GET", "repos/OWNER/REPO/actions/runs"),
+					httpmock.JSONResponse(shared.RunsPayload{
+						WorkflowRuns: runs,
+					}))
+				reg.Register(
+					httpmock.REST("GET", "repos/OWNER/REPO/actions/runs/3"),
+					httpmock.JSONResponse(successfulRun))
+				reg.Register(
+					httpmock.GraphQL(`query PullRequestForRun`),
+					httpmock.StringResponse(`{"data":{"repository":{"pullRequests":{"nodes":[
+						{"number":21,"title":"cool feature"}
+					]}}}}`))
+				reg.Register(
+					httpmock.REST("GET", "runs/3/jobs"),
+					httpmock.JSONResponse(shared.JobsPayload{
+						Jobs: []shared.Job{
+							{
+								ID:          10,
+								Status:      shared.Completed,
+								Conclusion:  shared.Success,
+								Name:        "cool job",
+								StartedAt:   created,
+								CompletedAt: updated,
+							},
+						},
+					}))
+				reg.Register(
+					httpmock.REST("GET", "repos/OWNER/REPO/check-runs/10/annotations"),
+					httpmock.JSONResponse([]shared.Annotation{}))
+			},
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOne(2)
+			},
+			opts: &ViewOptions{
+				Prompt:       true,
+				ShowProgress: true,
+			},
+			wantOut: "\n✓ trunk successful · 3\nTriggered via push about 59 minutes ago\nRelated pull request: #21 cool feature\n\nJOBS\n✓ cool job (ID 10)\n\nFor more information about a job, try: gh job view <job-id>\nview this run on GitHub: runs/3\n",
+		},
+		{
+			name: "did not find matching PR",
 			tty:  true,
 			stubs: func(reg *httpmock.Registry) {
 				reg.Register(
@@ -0,0 +1,187 @@
+package view
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cli/cli/internal/ghrepo"
+	"github.com/cli/cli/pkg/cmd/run/shared"
+	"github.com/cli/cli/pkg/httpmock"
+	"github.com/cli/cli/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunView_watch(t *testing.T) {
+	created, _ := time.Parse("2006-01-02 15:04:05", "2021-02-23 04:51:00")
+	updated, _ := time.Parse("2006-01-02 15:04:05", "2021-02-23 04:55:34")
+	now, _ := time.Parse("2006-01-02 15:04:05", "2021-02-23 05:50:00")
+
+	runningRun := shared.Run{
+		Name:       "in progress",
+		ID:         3,
+		CreatedAt:  created,
+		UpdatedAt:  updated,
+		Status:     shared.InProgress,
+		Event:      "push",
+		HeadBranch: "trunk",
+		JobsURL:    "/runs/3/jobs",
+		URL:        "runs/3",
+	}
+
+	finishedRun := runningRun
+	finishedRun.Status = shared.Completed
+	finishedRun.Conclusion = shared.Success
+
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	// initial fetch in runView, still in progress
+	reg.Register(
+		httpmock.REST("GET", "repos/OWNER/REPO/actions/runs/3"),
+		httpmock.JSONResponse(runningRun))
+	// watchRun's poll of the jobs while in progress
+	reg.Register(
+		httpmock.REST("GET", "runs/3/jobs"),
+		httpmock.JSONResponse(shared.JobsPayload{
+			Jobs: []shared.Job{
+				{ID: 10, Status: shared.InProgress, Name: "cool job"},
+			},
+		}))
+	// watchRun's re-fetch of the run once it has concluded
+	reg.Register(
+		httpmock.REST("GET", "repos/OWNER/REPO/actions/runs/3"),
+		httpmock.JSONResponse(finishedRun))
+	// the single, final render: jobs, PR lookup, annotations
+	reg.Register(
+		httpmock.REST("GET", "runs/3/jobs"),
+		httpmock.JSONResponse(shared.JobsPayload{
+			Jobs: []shared.Job{
+				{ID: 10, Status: shared.Completed, Conclusion: shared.Success, Name: "cool job"},
+			},
+		}))
+	reg.Register(
+		httpmock.GraphQL(`query PullRequestForRun`),
+		httpmock.StringResponse(``))
+	reg.Register(
+		httpmock.REST("GET", "repos/OWNER/REPO/check-runs/10/annotations"),
+		httpmock.JSONResponse([]shared.Annotation{}))
+
+	io, _, stdout, _ := iostreams.Test()
+	io.SetStdoutTTY(false)
+
+	opts := &ViewOptions{
+		RunID:      "3",
+		Watch:      true,
+		IO:         io,
+		Now:        func() time.Time { return now },
+		Poll:       func() time.Duration { return time.Millisecond },
+		HttpClient: func() (*http.Client, error) { return &http.Client{Transport: reg}, nil },
+		BaseRepo:   func() (ghrepo.Interface, error) { return ghrepo.FromFullName("OWNER/REPO") },
+	}
+
+	err := runView(opts)
+	assert.NoError(t, err)
+
+	out := stdout.String()
+	assert.Contains(t, out, fmt.Sprintf("%s cool job (ID 10)", "-"))
+	assert.Contains(t, out, "✓ cool job (ID 10)")
+}
+
+func TestRunView_watch_failure(t *testing.T) {
+	created, _ := time.Parse("2006-01-02 15:04:05", "2021-02-23 04:51:00")
+	updated, _ := time.Parse("2006-01-02 15:04:05", "2021-02-23 04:55:34")
+	start, _ := time.Parse("2006-01-02 15:04:05", "2021-02-23 05:00:00")
+
+	runningRun := shared.Run{
+		Name:       "in progress",
+		ID:         4,
+		CreatedAt:  created,
+		UpdatedAt:  updated,
+		Status:     shared.InProgress,
+		Event:      "push",
+		HeadBranch: "trunk",
+		JobsURL:    "/runs/4/jobs",
+		URL:        "runs/4",
+	}
+
+	failedRun := runningRun
+	failedRun.Status = shared.Completed
+	failedRun.Conclusion = shared.Failure
+
+	inProgressJobs := shared.JobsPayload{
+		Jobs: []shared.Job{
+			{ID: 40, Status: shared.InProgress, Name: "flaky job", StartedAt: start},
+		},
+	}
+
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	// initial fetch in runView, still in progress
+	reg.Register(
+		httpmock.REST("GET", "repos/OWNER/REPO/actions/runs/4"),
+		httpmock.JSONResponse(runningRun))
+	// watchRun's first poll of the jobs while in progress
+	reg.Register(
+		httpmock.REST("GET", "runs/4/jobs"),
+		httpmock.JSONResponse(inProgressJobs))
+	// watchRun's re-fetch of the run: still in progress, so it polls again
+	reg.Register(
+		httpmock.REST("GET", "repos/OWNER/REPO/actions/runs/4"),
+		httpmock.JSONResponse(runningRun))
+	// watchRun's second poll of the jobs while in progress
+	reg.Register(
+		httpmock.REST("GET", "runs/4/jobs"),
+		httpmock.JSONResponse(inProgressJobs))
+	// watchRun's re-fetch of the run once it has failed
+	reg.Register(
+		httpmock.REST("GET", "repos/OWNER/REPO/actions/runs/4"),
+		httpmock.JSONResponse(failedRun))
+	// the single, final render: jobs, PR lookup, annotations
+	reg.Register(
+		httpmock.REST("GET", "runs/4/jobs"),
+		httpmock.JSONResponse(shared.JobsPayload{
+			Jobs: []shared.Job{
+				{ID: 40, Status: shared.Completed, Conclusion: shared.Failure, Name: "flaky job", StartedAt: start, CompletedAt: start.Add(2 * time.Minute)},
+			},
+		}))
+	reg.Register(
+		httpmock.GraphQL(`query PullRequestForRun`),
+		httpmock.StringResponse(``))
+	reg.Register(
+		httpmock.REST("GET", "repos/OWNER/REPO/check-runs/40/annotations"),
+		httpmock.JSONResponse([]shared.Annotation{}))
+
+	io, _, stdout, _ := iostreams.Test()
+	io.SetStdoutTTY(false)
+
+	// advances the clock by a minute on every call, so each of the two
+	// in-progress polls renders a visibly different elapsed time
+	var pollCount int
+	opts := &ViewOptions{
+		RunID: "4",
+		Watch: true,
+		IO:    io,
+		Now: func() time.Time {
+			t := start.Add(time.Duration(pollCount) * time.Minute)
+			pollCount++
+			return t
+		},
+		Poll:       func() time.Duration { return time.Millisecond },
+		HttpClient: func() (*http.Client, error) { return &http.Client{Transport: reg}, nil },
+		BaseRepo:   func() (ghrepo.Interface, error) { return ghrepo.FromFullName("OWNER/REPO") },
+	}
+
+	err := runView(opts)
+	assert.NoError(t, err)
+
+	out := stdout.String()
+	// first poll: no time has elapsed yet
+	assert.Contains(t, out, "flaky job (ID 40) 0s")
+	// second poll: the clock has advanced, showing the run is still progressing
+	assert.Contains(t, out, "flaky job (ID 40) 1m0s")
+	// final render, after the run has failed
+	assert.Contains(t, out, "X flaky job (ID 40)")
+}